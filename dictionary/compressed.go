@@ -0,0 +1,225 @@
+package dictionary
+
+import "math/bits"
+
+const (
+	// compressedBlockBits (t) is the fixed block size CompressedDictionary
+	// partitions its bits into. 15 keeps per-block combinadic decoding cheap
+	// while still giving the class/offset split room to save space, and
+	// being 2^4-1, its classes 0..15 use every value of the 4-bit class field.
+	compressedBlockBits = 15
+
+	// compressedSuperblockBlocks (B) is the number of blocks per superblock.
+	// Rank/Bit rescan at most this many blocks past a cached superblock
+	// boundary, bounding them to O(1) regardless of the bit vector's size.
+	compressedSuperblockBlocks = 32
+)
+
+// compressedClassWidth is the number of bits needed to store a block's class
+// (its popcount, 0..compressedBlockBits), i.e. ⌈log2(compressedBlockBits+1)⌉.
+var compressedClassWidth = uint(bits.Len(uint(compressedBlockBits)))
+
+// compressedBinom is a precomputed Pascal's triangle up to
+// compressedBlockBits, used to convert between a block's bit pattern and its
+// (class, offset) pair via the combinatorial number system.
+var compressedBinom = newBinomialTable(compressedBlockBits)
+
+func newBinomialTable(t int) [][]int {
+	c := make([][]int, t+1)
+	for row := range c {
+		c[row] = make([]int, t+1)
+		c[row][0] = 1
+		for col := 1; col <= row; col++ {
+			c[row][col] = c[row-1][col-1]
+			if col <= row-1 {
+				c[row][col] += c[row-1][col]
+			}
+		}
+	}
+	return c
+}
+
+// CompressedDictionary is an RRR-style entropy-compressed bit vector: an
+// alternative to Dictionary that uses roughly nH0(B) + o(n) bits instead of
+// n + O(n/log n), at its best on skewed inputs where the zero-order entropy
+// H0 is much less than 1.
+//
+// Bits are partitioned into fixed-size blocks of compressedBlockBits bits.
+// Each block is stored as a (class, offset) pair: class is the block's
+// popcount, and offset is the block pattern's rank, via the combinatorial
+// number system, among all patterns sharing that class (in colex order).
+// Classes take a fixed ⌈log2(t+1)⌉ bits; offsets take only
+// ⌈log2(C(t,class))⌉ bits, so blocks with skewed popcounts cost far less than
+// a full t bits. Superblocks of compressedSuperblockBlocks blocks cache
+// cumulative rank and cumulative offset-stream bit position, so Rank/Bit only
+// need to rescan one superblock's worth of blocks to decode any one of them.
+type CompressedDictionary struct {
+	// n is the number of bits in the bit vector.
+	n int
+	// classes holds each block's popcount, compressedClassWidth bits each.
+	classes *packedInts
+	// offsetBits holds each block's offset, packed back-to-back at whatever
+	// width its class requires (see offsetWidth).
+	offsetBits []uint64
+	// superRank[i] is the cumulative popcount before superblock i's first block.
+	superRank []int
+	// superBitOffset[i] is the cumulative offsetBits bit position before
+	// superblock i's first block.
+	superBitOffset []int
+}
+
+// offsetWidth returns the number of bits needed to store the offset of a
+// block with the given class: ⌈log2(C(t,class))⌉.
+func offsetWidth(class int) uint {
+	return uint(bits.Len(uint(compressedBinom[compressedBlockBits][class] - 1)))
+}
+
+// blockPattern extracts the compressedBlockBits-wide bit pattern for block bi
+// from bits, treating positions beyond len(bits) as 0 for a partial last block.
+func blockPattern(bs []bool, bi int) uint32 {
+	var pattern uint32
+	base := bi * compressedBlockBits
+	for j := 0; j < compressedBlockBits; j++ {
+		if pos := base + j; pos < len(bs) && bs[pos] {
+			pattern |= 1 << uint(j)
+		}
+	}
+	return pattern
+}
+
+// encodeOffset returns the colex rank of pattern among all compressedBlockBits-bit
+// patterns with the given popcount (class), via the combinatorial number system.
+func encodeOffset(pattern uint32, class int) int {
+	offset := 0
+	i := 1
+	for pos := 0; pos < compressedBlockBits; pos++ {
+		if pattern&(1<<uint(pos)) != 0 {
+			offset += compressedBinom[pos][i]
+			i++
+		}
+	}
+	return offset
+}
+
+// decodeBlockPattern is the inverse of encodeOffset: it reconstructs the
+// compressedBlockBits-bit pattern with the given class and colex rank offset.
+func decodeBlockPattern(class, offset int) uint32 {
+	var pattern uint32
+	rem := offset
+	for i := class; i >= 1; i-- {
+		a := i - 1
+		for a+1 <= compressedBlockBits-1 && compressedBinom[a+1][i] <= rem {
+			a++
+		}
+		pattern |= 1 << uint(a)
+		rem -= compressedBinom[a][i]
+	}
+	return pattern
+}
+
+// NewCompressed builds a CompressedDictionary from bits using RRR-style
+// entropy compression.
+func NewCompressed(bs []bool) *CompressedDictionary {
+	n := len(bs)
+	nBlocks := (n + compressedBlockBits - 1) / compressedBlockBits
+
+	classOf := make([]int, nBlocks)
+	offsetOf := make([]int, nBlocks)
+	widthOf := make([]uint, nBlocks)
+	for bi := 0; bi < nBlocks; bi++ {
+		pattern := blockPattern(bs, bi)
+		class := bits.OnesCount32(pattern)
+		classOf[bi] = class
+		offsetOf[bi] = encodeOffset(pattern, class)
+		widthOf[bi] = offsetWidth(class)
+	}
+
+	nSuper := (nBlocks + compressedSuperblockBlocks - 1) / compressedSuperblockBlocks
+	superRank := make([]int, nSuper+1)
+	superBitOffset := make([]int, nSuper+1)
+
+	rankAcc, bitAcc := 0, 0
+	for bi := 0; bi < nBlocks; bi++ {
+		if bi%compressedSuperblockBlocks == 0 {
+			si := bi / compressedSuperblockBlocks
+			superRank[si] = rankAcc
+			superBitOffset[si] = bitAcc
+		}
+		rankAcc += classOf[bi]
+		bitAcc += int(widthOf[bi])
+	}
+	superRank[nSuper] = rankAcc
+	superBitOffset[nSuper] = bitAcc
+
+	classes := newPackedInts(nBlocks, compressedClassWidth)
+	offsetBits := make([]uint64, (bitAcc+63)/64)
+	pos := 0
+	for bi := 0; bi < nBlocks; bi++ {
+		classes.set(bi, uint64(classOf[bi]))
+		writeBitsAt(offsetBits, pos, widthOf[bi], uint64(offsetOf[bi]))
+		pos += int(widthOf[bi])
+	}
+
+	return &CompressedDictionary{
+		n:              n,
+		classes:        classes,
+		offsetBits:     offsetBits,
+		superRank:      superRank,
+		superBitOffset: superBitOffset,
+	}
+}
+
+// Len returns the total number of bits in the bit vector.
+func (c *CompressedDictionary) Len() int {
+	return c.n
+}
+
+// blockPatternAt decodes block bi's bit pattern, rescanning forward from its
+// superblock's cached bit offset to find where bi's own offset is stored.
+func (c *CompressedDictionary) blockPatternAt(bi int) uint32 {
+	si := bi / compressedSuperblockBlocks
+	pos := c.superBitOffset[si]
+	for k := si * compressedSuperblockBlocks; k < bi; k++ {
+		pos += int(offsetWidth(int(c.classes.get(k))))
+	}
+	class := int(c.classes.get(bi))
+	offset := int(readBitsAt(c.offsetBits, pos, offsetWidth(class)))
+	return decodeBlockPattern(class, offset)
+}
+
+// Bit returns true if the bit at the given position is 1, and false otherwise.
+func (c *CompressedDictionary) Bit(pos int) bool {
+	bi, j := pos/compressedBlockBits, pos%compressedBlockBits
+	return c.blockPatternAt(bi)&(1<<uint(j)) != 0
+}
+
+// Rank returns the number of 1-bits up to and including the given position.
+func (c *CompressedDictionary) Rank(pos int) int {
+	bi, j := pos/compressedBlockBits, pos%compressedBlockBits
+	si := bi / compressedSuperblockBlocks
+	rank := c.superRank[si]
+	for k := si * compressedSuperblockBlocks; k < bi; k++ {
+		rank += int(c.classes.get(k))
+	}
+	rank += bits.OnesCount32(c.blockPatternAt(bi) & (1<<uint(j+1) - 1))
+	return rank
+}
+
+// Rank0 returns the number of 0-bits up to and including the given position.
+func (c *CompressedDictionary) Rank0(pos int) int {
+	return pos - c.Rank(pos) + 1
+}
+
+// Select returns the smallest position of the 1-bit with the specified rank.
+// CompressedDictionary keeps no select index, so this falls back to binary
+// search over Rank rather than running in O(1).
+func (c *CompressedDictionary) Select(rank int) int {
+	return selectByRank(c.n, rank, c.Rank)
+}
+
+// Select0 returns the smallest position of the 0-bit with the specified rank.
+// CompressedDictionary keeps no select index, so this falls back to binary
+// search over Rank0 rather than running in O(1).
+func (c *CompressedDictionary) Select0(rank int) int {
+	return selectByRank(c.n, rank, c.Rank0)
+}
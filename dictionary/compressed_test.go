@@ -0,0 +1,209 @@
+package dictionary
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// skewedBits returns size bits where each bit is 1 with the given probability,
+// for exercising CompressedDictionary's entropy compression on low-H0 inputs.
+func skewedBits(t testing.TB, size int, p float64) []bool {
+	t.Helper()
+	b := make([]bool, size)
+	for i := range b {
+		b[i] = rand.Float64() < p
+	}
+	return b
+}
+
+func TestCompressedLen(t *testing.T) {
+	tests := map[string][]bool{
+		"empty":    {},
+		"all zero": zeroBits(t, 1000),
+		"skewed":   skewedBits(t, 100000, 0.03),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := NewCompressed(test)
+			if got, want := c.Len(), len(test); got != want {
+				t.Fatalf("Len() = %d; want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestCompressedBit(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+		"skewed bits":   skewedBits(t, size, 0.03),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := NewCompressed(test)
+			for i, want := range test {
+				if got := c.Bit(i); got != want {
+					t.Fatalf("Bit(%d) = %t; want %t", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompressedRank(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+		"skewed bits":   skewedBits(t, size, 0.03),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := NewCompressed(test)
+			want := 0
+			for i, b := range test {
+				if b {
+					want++
+				}
+				if got := c.Rank(i); got != want {
+					t.Fatalf("Rank(%d) = %d; want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompressedRank0(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"skewed bits":   skewedBits(t, size, 0.03),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := NewCompressed(test)
+			want := 0
+			for i, b := range test {
+				if !b {
+					want++
+				}
+				if got := c.Rank0(i); got != want {
+					t.Fatalf("Rank0(%d) = %d; want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompressedSelect(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"skewed bits":   skewedBits(t, size, 0.03),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := NewCompressed(test)
+			rank := 0
+			td := make(map[int]int)
+			for i, b := range test {
+				if b {
+					rank++
+					td[rank] = i
+				}
+			}
+			td[len(test)+100] = c.Len()
+			for rank, want := range td {
+				if got := c.Select(rank); got != want {
+					t.Fatalf("Select(%d) = %d; want %d", rank, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompressedSelect0(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"skewed bits":   skewedBits(t, size, 0.03),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := NewCompressed(test)
+			rank := 0
+			td := make(map[int]int)
+			for i, b := range test {
+				if !b {
+					rank++
+					td[rank] = i
+				}
+			}
+			td[len(test)+100] = c.Len()
+			for rank, want := range td {
+				if got := c.Select0(rank); got != want {
+					t.Fatalf("Select0(%d) = %d; want %d", rank, got, want)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkCompressedVsDenseSize reports the bytes used by CompressedDictionary
+// against Dictionary on a skewed (H0 << 1) input, where RRR-style compression
+// is expected to pay off.
+func BenchmarkCompressedVsDenseSize(b *testing.B) {
+	size := 10000000
+	bs := skewedBits(b, size, 0.03)
+
+	c := NewCompressed(bs)
+	compressedBytes := len(c.classes.words)*8 + len(c.offsetBits)*8
+
+	d := newDictionary(b, bs)
+	denseBytes := len(d.bits) * 8
+
+	b.ReportMetric(float64(compressedBytes), "compressed-bytes")
+	b.ReportMetric(float64(denseBytes), "dense-bytes")
+}
+
+func BenchmarkCompressedRank(b *testing.B) {
+	size := 10000000
+	c := NewCompressed(skewedBits(b, size, 0.03))
+	r := rand.Intn(c.Len())
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Rank(r)
+		}
+	})
+}
+
+func BenchmarkDenseRankSkewed(b *testing.B) {
+	size := 10000000
+	d := newDictionary(b, skewedBits(b, size, 0.03))
+	r := rand.Intn(size)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			d.Rank(r)
+		}
+	})
+}
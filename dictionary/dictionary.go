@@ -17,31 +17,63 @@ import (
 )
 
 const (
-	// bitsSize represents the number of bits in each element of the underlying bit array (1 byte = 8 bits).
-	bitsSize = 8
+	// bitsSize represents the number of bits in each element of the underlying bit array (1 word = 64 bits).
+	bitsSize = 64
 
 	// bitsPerRankIndexLarge specifies the number of bit units that one large rank index entry spans.
-	bitsPerRankIndexLarge = 8191
+	bitsPerRankIndexLarge = 1023
+
+	// DefaultSs is the default select-sampling rate used by New: every Ss-th
+	// 1-bit (and 0-bit) is sampled during CreateIndex to make Select/Select0
+	// run in near-constant time. Smaller values trade memory for speed.
+	DefaultSs = 512
 )
 
 // Dictionary represents a succinct bit vector with rank and select operations.
-// It stores the bits as a slice of bytes and maintains rank indexes for efficient queries.
+// It stores the bits packed into 64-bit words and maintains rank indexes for efficient queries.
 type Dictionary struct {
-	// Array of bits stored in bytes.
-	bits []uint8
+	// Array of bits packed into words.
+	bits []uint64
 	// Rank index structure for efficient rank operations.
 	rank rankIndex
+	// Select-sampling index structure for efficient select operations.
+	sel selectIndex
 }
 
 // New creates a new Dictionary of the specified size.
 // It initializes the bit vector and prepares it for bit manipulations.
+// Select/Select0 use DefaultSs as their select-sampling rate; use NewWithSs
+// to choose a different memory/speed trade-off.
 func New(size int) *Dictionary {
+	return NewWithSs(size, DefaultSs)
+}
+
+// NewWithSs creates a new Dictionary of the specified size, sampling every
+// ss-th 1-bit (and 0-bit) for select acceleration. A smaller ss speeds up
+// Select/Select0 at the cost of more memory spent on the sample index.
+func NewWithSs(size, ss int) *Dictionary {
 	d := new(Dictionary)
 	l := size / bitsSize
 	if size%bitsSize > 0 {
 		l++
 	}
-	d.bits = make([]uint8, l)
+	d.bits = make([]uint64, l)
+	d.sel.ss = ss
+	return d
+}
+
+// NewFromWords builds a Dictionary directly from words, which must already
+// hold bits packed 64 to a word, for callers who have packed data on hand
+// (e.g. read via mmap) and want to adopt it without copying. Only the first
+// ⌈bitLen/bitsSize⌉ words are kept, mirroring the rounding New applies to size.
+func NewFromWords(words []uint64, bitLen int) *Dictionary {
+	l := bitLen / bitsSize
+	if bitLen%bitsSize > 0 {
+		l++
+	}
+	d := new(Dictionary)
+	d.bits = words[:l]
+	d.sel.ss = DefaultSs
 	return d
 }
 
@@ -50,12 +82,12 @@ func (d *Dictionary) Len() int {
 	return len(d.bits) * bitsSize
 }
 
-// bitsIndex returns the index in the byte array (bits) that corresponds to the given bit position.
+// bitsIndex returns the index in the word array (bits) that corresponds to the given bit position.
 func (d *Dictionary) bitsIndex(pos int) int {
 	return pos / bitsSize
 }
 
-// bitPos returns the position of a specific bit within a byte in the bit array.
+// bitPos returns the position of a specific bit within a word in the bit array.
 func (d *Dictionary) bitPos(pos int) int {
 	return pos % bitsSize
 }
@@ -64,7 +96,7 @@ func (d *Dictionary) bitPos(pos int) int {
 // The flag parameter determines whether to set or clear the bit.
 func (d *Dictionary) SetBit(pos int, flag bool) {
 	bi := d.bitsIndex(pos)
-	var b uint8 = 1 << d.bitPos(pos)
+	var b uint64 = 1 << uint(d.bitPos(pos))
 	if flag {
 		d.bits[bi] |= b
 	} else {
@@ -72,18 +104,50 @@ func (d *Dictionary) SetBit(pos int, flag bool) {
 	}
 }
 
+// SetWord sets the wordIdx-th 64-bit word of the underlying storage directly,
+// for callers building large vectors who already have their data packed 64
+// bits at a time and want to skip the per-bit overhead of SetBit.
+func (d *Dictionary) SetWord(wordIdx int, w uint64) {
+	d.bits[wordIdx] = w
+}
+
 // Bit returns true if the bit at the given position is 1, and false otherwise.
 func (d *Dictionary) Bit(pos int) bool {
-	return d.bits[d.bitsIndex(pos)]&(1<<d.bitPos(pos)) > 0
+	return d.bits[d.bitsIndex(pos)]&(1<<uint(d.bitPos(pos))) > 0
 }
 
-// CreateIndex builds the index for efficient rank and select operations.
+// CreateIndex builds the index for efficient rank and select operations. It
+// processes 8 words at a time, computing their popcounts up front
+// so the per-word OnesCount64 calls have no dependency on one another before
+// being folded into the running cumulative count.
 func (d *Dictionary) CreateIndex() {
 	d.rank = newRankIndex(d.Len())
+	d.sel = newSelectIndex(d.sel.ss)
 	c := 0
-	for i, b := range d.bits {
-		c += oneBitsCount(b, bitsSize-1)
+	n := len(d.bits)
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		var pc [8]int
+		pc[0] = bits.OnesCount64(d.bits[i])
+		pc[1] = bits.OnesCount64(d.bits[i+1])
+		pc[2] = bits.OnesCount64(d.bits[i+2])
+		pc[3] = bits.OnesCount64(d.bits[i+3])
+		pc[4] = bits.OnesCount64(d.bits[i+4])
+		pc[5] = bits.OnesCount64(d.bits[i+5])
+		pc[6] = bits.OnesCount64(d.bits[i+6])
+		pc[7] = bits.OnesCount64(d.bits[i+7])
+		for j := 0; j < 8; j++ {
+			c += pc[j]
+			d.rank.update(i+j, c)
+			c0 := (i+j+1)*bitsSize - c
+			d.sel.update(i+j, c, c0)
+		}
+	}
+	for ; i < n; i++ {
+		c += bits.OnesCount64(d.bits[i])
 		d.rank.update(i, c)
+		c0 := (i+1)*bitsSize - c
+		d.sel.update(i, c, c0)
 	}
 }
 
@@ -103,83 +167,52 @@ func (d *Dictionary) Rank0(pos int) int {
 }
 
 // Select returns the smallest position of the 1-bit with the specified rank in the bit vector.
-// It efficiently finds the first occurrence of the specified number of set bits.
+// It jumps to the sampled word nearest the answer via the select index, scans forward a few
+// words using the cached rank values, and finishes with a per-word bit lookup.
 func (d *Dictionary) Select(rank int) (pos int) {
-	l, r := 0, d.Len()
-	for l != r {
-		m := (l + r) / 2
-		if d.Rank(m) < rank {
-			l = m + 1
-		} else {
-			r = m
-		}
+	if rank <= 0 {
+		return 0
+	}
+	if len(d.bits) == 0 {
+		return d.Len()
 	}
-	return l
+	bi := d.sel.sampleOne(rank)
+	for bi < len(d.bits)-1 && d.rank.rank(bi+1) < rank {
+		bi++
+	}
+	if d.rank.rank(bi+1) < rank {
+		return d.Len()
+	}
+	return bi*bitsSize + selectInWord(d.bits[bi], rank-d.rank.rank(bi))
 }
 
 // Select0 returns the smallest position of the 0-bit with the specified rank in the bit vector.
-// It efficiently finds the first occurrence of the specified number of set bits.
+// It jumps to the sampled word nearest the answer via the select index, scans forward a few
+// words using the cached rank values, and finishes with a per-word bit lookup.
 func (d *Dictionary) Select0(rank int) (pos int) {
-	l, r := 0, d.Len()
-	for l != r {
-		m := (l + r) / 2
-		if d.Rank0(m) < rank {
-			l = m + 1
-		} else {
-			r = m
-		}
-	}
-	return l
-}
-
-// rankIndex represents the structure used to store rank information for efficient rank query.
-type rankIndex struct {
-	// Small rank index storing cumulative counts within smaller intervals.
-	small []uint16
-	// Large rank index storing cumulative counts at larger intervals (e.g., every bitsPerRankIndexLarge bits).
-	large []int
-}
-
-// newRankIndex creates a new rankIndex of the specified size.
-func newRankIndex(size int) rankIndex {
-	sl := size/bitsSize + 1
-	if size%bitsSize != 0 {
-		sl++
+	if rank <= 0 {
+		return 0
 	}
-
-	ls := bitsSize * bitsPerRankIndexLarge
-	ll := size/ls + 1
-	if size%ls != 0 {
-		ll++
+	if len(d.bits) == 0 {
+		return d.Len()
 	}
-
-	return rankIndex{
-		small: make([]uint16, sl),
-		large: make([]int, ll),
+	bi := d.sel.sampleZero(rank)
+	for bi < len(d.bits)-1 && d.rank0AtWord(bi+1) < rank {
+		bi++
 	}
-}
-
-// largeIndex returns the index in the large rank index that corresponds to the given bitsIndex.
-func (r *rankIndex) largeIndex(bitsIndex int) int {
-	return bitsIndex / bitsPerRankIndexLarge
-}
-
-// update updates the rank index with the cumulative count of 1-bits at the given bitsIndex.
-func (r *rankIndex) update(bitsIndex, onesCount int) {
-	li := r.largeIndex(bitsIndex)
-	if bitsIndex%bitsPerRankIndexLarge == bitsPerRankIndexLarge-1 {
-		r.large[li+1] = onesCount
-		return
+	if d.rank0AtWord(bi+1) < rank {
+		return d.Len()
 	}
-	r.small[bitsIndex+1] = uint16(onesCount - r.large[li])
+	return bi*bitsSize + selectInWord(^d.bits[bi], rank-d.rank0AtWord(bi))
 }
 
-// rank returns the cumulative number of 1-bits up to the given bitsIndex.
-func (r *rankIndex) rank(bitsIndex int) int {
-	return int(r.large[r.largeIndex(bitsIndex)] + int(r.small[bitsIndex]))
+// rank0AtWord returns the cumulative number of 0-bits in the words before bi, mirroring
+// rankIndex.rank but for zeros, which aren't stored directly since they're derivable.
+func (d *Dictionary) rank0AtWord(bi int) int {
+	return bi*bitsSize - d.rank.rank(bi)
 }
 
-// oneBitsCount returns the number of 1-bits in the byte x, up to the given bit position pos.
-func oneBitsCount(x uint8, pos int) int {
-	return bits.OnesCount8(x & uint8((1<<(pos+1))-1))
+// oneBitsCount returns the number of 1-bits in the word x, up to the given bit position pos.
+func oneBitsCount(x uint64, pos int) int {
+	return bits.OnesCount64(x & (1<<(pos+1) - 1))
 }
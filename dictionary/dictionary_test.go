@@ -64,6 +64,31 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewFromWords(t *testing.T) {
+	size := 1000000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			src := newDictionary(t, test)
+			d := NewFromWords(src.bits, len(test))
+			if got, want := d.Len(), src.Len(); got != want {
+				t.Fatalf("d.Len() = %d; want %d", got, want)
+			}
+			for i, want := range test {
+				if got := d.Bit(i); got != want {
+					t.Fatalf("d.Bit(%d) = %t; want %t", i, got, want)
+				}
+			}
+		})
+	}
+}
+
 func assertSetBit(t *testing.T, d *Dictionary, wants []bool) {
 	t.Helper()
 	gots := make([]bool, len(wants))
@@ -122,6 +147,27 @@ func TestSetBitZero(t *testing.T) {
 	}
 }
 
+func TestSetWord(t *testing.T) {
+	size := 1000000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			want := newDictionary(t, test)
+			d := New(len(test))
+			for i, w := range want.bits {
+				d.SetWord(i, w)
+			}
+			assertSetBit(t, d, test)
+		})
+	}
+}
+
 func TestBit(t *testing.T) {
 	size := 1000000
 	tests := map[string][]bool{
@@ -284,6 +330,16 @@ func TestSelect0(t *testing.T) {
 	}
 }
 
+func TestSelectEmpty(t *testing.T) {
+	d := newDictionary(t, nil)
+	if got, want := d.Select(1), d.Len(); got != want {
+		t.Fatalf("d.Select(1) = %d; want %d", got, want)
+	}
+	if got, want := d.Select0(1), d.Len(); got != want {
+		t.Fatalf("d.Select0(1) = %d; want %d", got, want)
+	}
+}
+
 func BenchmarkCreateIndex(b *testing.B) {
 	size := 10000000
 	r := randBits(b, size)
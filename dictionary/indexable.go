@@ -0,0 +1,111 @@
+package dictionary
+
+// Indexable is the read-only rank/select query API shared by Dictionary,
+// SparseDictionary and CompressedDictionary, letting callers swap the dense,
+// Elias-Fano and RRR-style backends without caring which one they hold.
+type Indexable interface {
+	// Rank returns the number of 1-bits up to and including the given position.
+	Rank(pos int) int
+	// Rank0 returns the number of 0-bits up to and including the given position.
+	Rank0(pos int) int
+	// Select returns the smallest position of the 1-bit with the specified rank.
+	Select(rank int) int
+	// Select0 returns the smallest position of the 0-bit with the specified rank.
+	Select0(rank int) int
+	// Bit returns true if the bit at the given position is 1, and false otherwise.
+	Bit(pos int) bool
+	// Len returns the total number of bits in the bit vector.
+	Len() int
+}
+
+var (
+	_ Indexable = (*Dictionary)(nil)
+	_ Indexable = (*SparseDictionary)(nil)
+	_ Indexable = (*CompressedDictionary)(nil)
+	_ Indexable = complementSparse{}
+)
+
+// sparseDensityDivisor sets the density threshold used by NewAuto: inputs
+// with fewer than one 1-bit (or, symmetrically, one 0-bit) per
+// sparseDensityDivisor bits are built as a SparseDictionary, since
+// Elias-Fano's space is proportional to the number of bits it stores rather
+// than the universe size.
+const sparseDensityDivisor = 8
+
+// NewAuto builds the Dictionary or SparseDictionary backend best suited to the
+// measured density of bits: sparse inputs are encoded with Elias-Fano via
+// NewSparse, very dense inputs are encoded the same way by applying
+// Elias-Fano to the complement (storing the rare 0-bits instead of the
+// plentiful 1-bits) via complementSparse, and everything else uses the dense
+// Dictionary via New.
+func NewAuto(bits []bool) Indexable {
+	n := 0
+	for _, b := range bits {
+		if b {
+			n++
+		}
+	}
+	u := len(bits)
+	if u > 0 && n*sparseDensityDivisor < u {
+		return NewSparse(bits)
+	}
+	if u > 0 && (u-n)*sparseDensityDivisor < u {
+		complement := make([]bool, u)
+		for i, b := range bits {
+			complement[i] = !b
+		}
+		return complementSparse{s: NewSparse(complement)}
+	}
+
+	d := New(u)
+	for i, b := range bits {
+		d.SetBit(i, b)
+	}
+	d.CreateIndex()
+	return d
+}
+
+// complementSparse adapts a SparseDictionary encoding of a bit vector's
+// complement back into an Indexable view of the original vector, by swapping
+// which query answers which bit value: it's how NewAuto applies Elias-Fano
+// to very dense inputs, where the 0-bits are sparse even though the 1-bits
+// aren't.
+type complementSparse struct {
+	s *SparseDictionary
+}
+
+// Len returns the total number of bits in the bit vector.
+func (c complementSparse) Len() int { return c.s.Len() }
+
+// Bit returns true if the bit at the given position is 1, and false otherwise.
+func (c complementSparse) Bit(pos int) bool { return !c.s.Bit(pos) }
+
+// Rank returns the number of 1-bits up to and including the given position.
+func (c complementSparse) Rank(pos int) int { return c.s.Rank0(pos) }
+
+// Rank0 returns the number of 0-bits up to and including the given position.
+func (c complementSparse) Rank0(pos int) int { return c.s.Rank(pos) }
+
+// Select returns the smallest position of the 1-bit with the specified rank.
+func (c complementSparse) Select(rank int) int { return c.s.Select0(rank) }
+
+// Select0 returns the smallest position of the 0-bit with the specified rank.
+func (c complementSparse) Select0(rank int) int { return c.s.Select(rank) }
+
+// selectByRank finds the smallest position in [0, hi] whose rankAt reaches
+// rank, by binary search. rankAt must be monotonically non-decreasing over
+// [0, hi]. Backends without their own select index (SparseDictionary's
+// Select0, CompressedDictionary's Select/Select0) use this as a correct, if
+// not O(1), fallback.
+func selectByRank(hi, rank int, rankAt func(pos int) int) int {
+	l, r := 0, hi
+	for l != r {
+		m := (l + r) / 2
+		if rankAt(m) < rank {
+			l = m + 1
+		} else {
+			r = m
+		}
+	}
+	return l
+}
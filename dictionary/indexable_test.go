@@ -0,0 +1,129 @@
+package dictionary
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// denseBits returns size bits with roughly one 0-bit per every 64 positions,
+// the complement-density mirror of sparseBits, for exercising NewAuto's very
+// dense path.
+func denseBits(t testing.TB, size int) []bool {
+	t.Helper()
+	b := sparseBits(t, size)
+	for i := range b {
+		b[i] = !b[i]
+	}
+	return b
+}
+
+func TestNewAuto(t *testing.T) {
+	size := 100000
+	tests := map[string]struct {
+		bits []bool
+		want Indexable
+	}{
+		"sparse bits pick SparseDictionary": {
+			bits: sparseBits(t, size),
+			want: &SparseDictionary{},
+		},
+		"dense bits pick Dictionary": {
+			bits: randBits(t, size),
+			want: &Dictionary{},
+		},
+		"very dense bits pick complement-encoded SparseDictionary": {
+			bits: denseBits(t, size),
+			want: complementSparse{},
+		},
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := NewAuto(test.bits)
+			switch test.want.(type) {
+			case *SparseDictionary:
+				if _, ok := got.(*SparseDictionary); !ok {
+					t.Fatalf("NewAuto() = %T; want *SparseDictionary", got)
+				}
+			case *Dictionary:
+				if _, ok := got.(*Dictionary); !ok {
+					t.Fatalf("NewAuto() = %T; want *Dictionary", got)
+				}
+			case complementSparse:
+				if _, ok := got.(complementSparse); !ok {
+					t.Fatalf("NewAuto() = %T; want complementSparse", got)
+				}
+			}
+			for i, b := range test.bits {
+				if got.Bit(i) != b {
+					t.Fatalf("Bit(%d) = %t; want %t", i, got.Bit(i), b)
+				}
+			}
+		})
+	}
+}
+
+func TestComplementSparseRankSelect(t *testing.T) {
+	size := 100000
+	test := denseBits(t, size)
+	idx := NewAuto(test)
+	if _, ok := idx.(complementSparse); !ok {
+		t.Fatalf("NewAuto() = %T; want complementSparse", idx)
+	}
+
+	rank, rank0 := 0, 0
+	onesAt, zerosAt := map[int]int{}, map[int]int{}
+	for i, b := range test {
+		if b {
+			rank++
+			onesAt[rank] = i
+		} else {
+			rank0++
+			zerosAt[rank0] = i
+		}
+		if got := idx.Rank(i); got != rank {
+			t.Fatalf("Rank(%d) = %d; want %d", i, got, rank)
+		}
+		if got := idx.Rank0(i); got != rank0 {
+			t.Fatalf("Rank0(%d) = %d; want %d", i, got, rank0)
+		}
+	}
+	onesAt[rank+100] = idx.Len()
+	zerosAt[rank0+100] = idx.Len()
+	for r, want := range onesAt {
+		if got := idx.Select(r); got != want {
+			t.Fatalf("Select(%d) = %d; want %d", r, got, want)
+		}
+	}
+	for r, want := range zerosAt {
+		if got := idx.Select0(r); got != want {
+			t.Fatalf("Select0(%d) = %d; want %d", r, got, want)
+		}
+	}
+}
+
+func TestNewAutoEmpty(t *testing.T) {
+	got := NewAuto(nil)
+	if got.Len() != 0 {
+		t.Fatalf("Len() = %d; want 0", got.Len())
+	}
+}
+
+func FuzzNewAuto(f *testing.F) {
+	for _, size := range []uint32{0, 1, 10, 100, 1000, 10000} {
+		f.Add(size)
+	}
+	f.Fuzz(func(t *testing.T, size uint32) {
+		bs := make([]bool, size)
+		for i := range bs {
+			bs[i] = rand.Int()%2 == 0
+		}
+		idx := NewAuto(bs)
+		for i, b := range bs {
+			if idx.Bit(i) != b {
+				t.Fatalf("size = %d; Bit(%d) = %t; want %t", size, i, idx.Bit(i), b)
+			}
+		}
+	})
+}
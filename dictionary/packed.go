@@ -0,0 +1,60 @@
+package dictionary
+
+// packedInts stores a sequence of fixed-width unsigned integers packed
+// contiguously into a []uint64 backing array, width bits per entry. It
+// underlies the low parts of SparseDictionary's Elias-Fano encoding, where
+// entries are only a handful of bits wide and a byte (or word) per entry
+// would waste most of the space succinctness is meant to save.
+type packedInts struct {
+	words []uint64
+	width uint
+}
+
+// newPackedInts allocates storage for n entries of the given bit width.
+func newPackedInts(n int, width uint) *packedInts {
+	bitsTotal := n * int(width)
+	nWords := (bitsTotal + 63) / 64
+	return &packedInts{words: make([]uint64, nWords), width: width}
+}
+
+// set stores the low p.width bits of v as the i-th entry.
+func (p *packedInts) set(i int, v uint64) {
+	writeBitsAt(p.words, i*int(p.width), p.width, v)
+}
+
+// get returns the i-th entry.
+func (p *packedInts) get(i int) uint64 {
+	return readBitsAt(p.words, i*int(p.width), p.width)
+}
+
+// writeBitsAt stores the low width bits of v at bitPos in words, a flat bit
+// stream used wherever entries are packed contiguously but (unlike
+// packedInts) don't all share the same width, such as CompressedDictionary's
+// per-block offsets.
+func writeBitsAt(words []uint64, bitPos int, width uint, v uint64) {
+	if width == 0 {
+		return
+	}
+	wordIdx := bitPos / 64
+	bitOff := uint(bitPos % 64)
+	v &= uint64(1)<<width - 1
+	words[wordIdx] |= v << bitOff
+	if bitOff+width > 64 {
+		words[wordIdx+1] |= v >> (64 - bitOff)
+	}
+}
+
+// readBitsAt returns the width bits stored at bitPos in words by writeBitsAt.
+func readBitsAt(words []uint64, bitPos int, width uint) uint64 {
+	if width == 0 {
+		return 0
+	}
+	wordIdx := bitPos / 64
+	bitOff := uint(bitPos % 64)
+	mask := uint64(1)<<width - 1
+	v := words[wordIdx] >> bitOff
+	if bitOff+width > 64 {
+		v |= words[wordIdx+1] << (64 - bitOff)
+	}
+	return v & mask
+}
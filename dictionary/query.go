@@ -0,0 +1,85 @@
+package dictionary
+
+// Predecessor returns the largest position at or before pos holding a 1-bit,
+// or -1 if there is none. It, Successor, and RankRange are the higher-level
+// primitives callers building wavelet trees, LOUDS trees, and inverted
+// indexes on top of an Indexable repeatedly need, expressed here once in
+// terms of Rank/Select so every backend gets them for free.
+func Predecessor(idx Indexable, pos int) int {
+	if idx.Bit(pos) {
+		return pos
+	}
+	r := idx.Rank(pos)
+	if r == 0 {
+		return -1
+	}
+	return idx.Select(r)
+}
+
+// Successor returns the smallest position at or after pos holding a 1-bit, or
+// idx.Len() if there is none.
+func Successor(idx Indexable, pos int) int {
+	if pos < idx.Len() && idx.Bit(pos) {
+		return pos
+	}
+	r := 0
+	if pos > 0 {
+		r = idx.Rank(pos - 1)
+	}
+	return idx.Select(r + 1)
+}
+
+// RankRange returns the number of 1-bits in the half-open range [lo, hi). It's
+// exposed as its own function, rather than left for callers to compute as two
+// Rank calls, so a future backend can fuse the pair (e.g. sharing a superblock
+// lookup when lo and hi fall in it) without changing this signature. Like the
+// other queries in this file, it is a package-level function over an
+// Indexable, not a method of any backend type, and always makes two Rank calls.
+func RankRange(idx Indexable, lo, hi int) int {
+	if lo >= hi {
+		return 0
+	}
+	r := idx.Rank(hi - 1)
+	if lo > 0 {
+		r -= idx.Rank(lo - 1)
+	}
+	return r
+}
+
+// Predecessor0 returns the largest position at or before pos holding a
+// 0-bit, or -1 if there is none.
+func Predecessor0(idx Indexable, pos int) int {
+	if !idx.Bit(pos) {
+		return pos
+	}
+	r := idx.Rank0(pos)
+	if r == 0 {
+		return -1
+	}
+	return idx.Select0(r)
+}
+
+// Successor0 returns the smallest position at or after pos holding a 0-bit,
+// or idx.Len() if there is none.
+func Successor0(idx Indexable, pos int) int {
+	if pos < idx.Len() && !idx.Bit(pos) {
+		return pos
+	}
+	r := 0
+	if pos > 0 {
+		r = idx.Rank0(pos - 1)
+	}
+	return idx.Select0(r + 1)
+}
+
+// RankRange0 returns the number of 0-bits in the half-open range [lo, hi).
+func RankRange0(idx Indexable, lo, hi int) int {
+	if lo >= hi {
+		return 0
+	}
+	r := idx.Rank0(hi - 1)
+	if lo > 0 {
+		r -= idx.Rank0(lo - 1)
+	}
+	return r
+}
@@ -0,0 +1,192 @@
+package dictionary
+
+import "testing"
+
+func backends(t testing.TB, bs []bool) map[string]Indexable {
+	t.Helper()
+	return map[string]Indexable{
+		"Dictionary":       newDictionary(t, bs),
+		"SparseDictionary": NewSparse(bs),
+	}
+}
+
+func TestPredecessor(t *testing.T) {
+	size := 10000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+		"sparse bits":   sparseBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			for bname, idx := range backends(t, test) {
+				last := -1
+				for i, b := range test {
+					if b {
+						last = i
+					}
+					if got, want := Predecessor(idx, i), last; got != want {
+						t.Fatalf("%s: Predecessor(%d) = %d; want %d", bname, i, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSuccessor(t *testing.T) {
+	size := 10000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+		"sparse bits":   sparseBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			for bname, idx := range backends(t, test) {
+				next := idx.Len()
+				for i := len(test) - 1; i >= 0; i-- {
+					if test[i] {
+						next = i
+					}
+					if got, want := Successor(idx, i), next; got != want {
+						t.Fatalf("%s: Successor(%d) = %d; want %d", bname, i, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRankRange(t *testing.T) {
+	size := 10000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+		"sparse bits":   sparseBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			for bname, idx := range backends(t, test) {
+				for _, lo := range []int{0, 1, len(test) / 3, len(test) / 2} {
+					for _, hi := range []int{len(test) / 3, len(test) / 2, len(test)} {
+						want := 0
+						for i := lo; i < hi; i++ {
+							if test[i] {
+								want++
+							}
+						}
+						if got := RankRange(idx, lo, hi); got != want {
+							t.Fatalf("%s: RankRange(%d, %d) = %d; want %d", bname, lo, hi, got, want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// withPadding extends bs with the false padding Dictionary implicitly appends
+// to round its storage up to a whole number of words, so tests that walk off
+// the end of the nominal size still see the bit vector's real contents.
+func withPadding(bs []bool, l int) []bool {
+	full := make([]bool, l)
+	copy(full, bs)
+	return full
+}
+
+func TestPredecessor0(t *testing.T) {
+	size := 10000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			idx := newDictionary(t, test)
+			full := withPadding(test, idx.Len())
+			last := -1
+			for i, b := range full {
+				if !b {
+					last = i
+				}
+				if i >= len(test) {
+					continue
+				}
+				if got, want := Predecessor0(idx, i), last; got != want {
+					t.Fatalf("Predecessor0(%d) = %d; want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSuccessor0(t *testing.T) {
+	size := 10000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			idx := newDictionary(t, test)
+			full := withPadding(test, idx.Len())
+			next := idx.Len()
+			for i := len(full) - 1; i >= 0; i-- {
+				if !full[i] {
+					next = i
+				}
+				if i >= len(test) {
+					continue
+				}
+				if got, want := Successor0(idx, i), next; got != want {
+					t.Fatalf("Successor0(%d) = %d; want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRankRange0(t *testing.T) {
+	size := 10000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			idx := newDictionary(t, test)
+			for _, lo := range []int{0, 1, len(test) / 3, len(test) / 2} {
+				for _, hi := range []int{len(test) / 3, len(test) / 2, len(test)} {
+					want := 0
+					for i := lo; i < hi; i++ {
+						if !test[i] {
+							want++
+						}
+					}
+					if got := RankRange0(idx, lo, hi); got != want {
+						t.Fatalf("RankRange0(%d, %d) = %d; want %d", lo, hi, got, want)
+					}
+				}
+			}
+		})
+	}
+}
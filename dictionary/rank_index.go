@@ -0,0 +1,87 @@
+package dictionary
+
+// largeRankWideThreshold is the smallest bit-vector size for which
+// newRankIndex switches from the 32-bit large rank index to the wider 64-bit
+// variant, mirroring index/suffixarray's automatic 32/64-bit selection based
+// on input size so that vectors under 2^32 bits use half the memory.
+const largeRankWideThreshold = 1 << 32
+
+// largeRank stores the cumulative rank counts sampled every bitsPerRankIndexLarge
+// words. It is implemented by largeRank32 and largeRank64 so that rankIndex can
+// pick the narrowest width that fits the bit vector's size.
+type largeRank interface {
+	get(i int) int
+	set(i, v int)
+	len() int
+}
+
+// largeRank32 is the large rank index backing used for bit vectors under 2^32 bits.
+type largeRank32 []uint32
+
+func (l largeRank32) get(i int) int { return int(l[i]) }
+func (l largeRank32) set(i, v int)  { l[i] = uint32(v) }
+func (l largeRank32) len() int      { return len(l) }
+
+// largeRank64 is the large rank index backing used for bit vectors of 2^32 bits or more.
+type largeRank64 []uint64
+
+func (l largeRank64) get(i int) int { return int(l[i]) }
+func (l largeRank64) set(i, v int)  { l[i] = uint64(v) }
+func (l largeRank64) len() int      { return len(l) }
+
+// rankIndex represents the structure used to store rank information for efficient rank query.
+type rankIndex struct {
+	// Small rank index storing cumulative counts within smaller intervals.
+	small []uint16
+	// Large rank index storing cumulative counts at larger intervals (e.g., every bitsPerRankIndexLarge words).
+	large largeRank
+}
+
+// newRankIndex creates a new rankIndex of the specified size.
+func newRankIndex(size int) rankIndex {
+	sl := size/bitsSize + 1
+	if size%bitsSize != 0 {
+		sl++
+	}
+
+	ls := bitsSize * bitsPerRankIndexLarge
+	ll := size/ls + 1
+	if size%ls != 0 {
+		ll++
+	}
+
+	return rankIndex{
+		small: make([]uint16, sl),
+		large: newLargeRank(ll, size),
+	}
+}
+
+// newLargeRank allocates a large rank index of length ll sized for a bit
+// vector of the given size: largeRank32 below largeRankWideThreshold, and
+// largeRank64 beyond it.
+func newLargeRank(ll, size int) largeRank {
+	if size < largeRankWideThreshold {
+		return make(largeRank32, ll)
+	}
+	return make(largeRank64, ll)
+}
+
+// largeIndex returns the index in the large rank index that corresponds to the given bitsIndex.
+func (r *rankIndex) largeIndex(bitsIndex int) int {
+	return bitsIndex / bitsPerRankIndexLarge
+}
+
+// update updates the rank index with the cumulative count of 1-bits at the given bitsIndex.
+func (r *rankIndex) update(bitsIndex, onesCount int) {
+	li := r.largeIndex(bitsIndex)
+	if bitsIndex%bitsPerRankIndexLarge == bitsPerRankIndexLarge-1 {
+		r.large.set(li+1, onesCount)
+		return
+	}
+	r.small[bitsIndex+1] = uint16(onesCount - r.large.get(li))
+}
+
+// rank returns the cumulative number of 1-bits up to the given bitsIndex.
+func (r *rankIndex) rank(bitsIndex int) int {
+	return r.large.get(r.largeIndex(bitsIndex)) + int(r.small[bitsIndex])
+}
@@ -0,0 +1,45 @@
+package dictionary
+
+import "testing"
+
+func TestNewLargeRank(t *testing.T) {
+	tests := map[string]struct {
+		size int
+		wide bool
+	}{
+		"below threshold": {size: largeRankWideThreshold - 1, wide: false},
+		"at threshold":    {size: largeRankWideThreshold, wide: true},
+		"above threshold": {size: largeRankWideThreshold + 1, wide: true},
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			large := newLargeRank(4, test.size)
+			_, wide := large.(largeRank64)
+			if wide != test.wide {
+				t.Fatalf("newLargeRank(4, %d) wide = %t; want %t", test.size, wide, test.wide)
+			}
+		})
+	}
+}
+
+// BenchmarkLargeRankMemory reports the bytes allocated by each large rank index
+// width for the same entry count, demonstrating the memory halved by picking
+// largeRank32 over largeRank64 for bit vectors under largeRankWideThreshold.
+func BenchmarkLargeRankMemory(b *testing.B) {
+	const entries = 1000000
+
+	b.Run("32-bit", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = make(largeRank32, entries)
+		}
+	})
+	b.Run("64-bit", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = make(largeRank64, entries)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package dictionary
+
+// selectIndex accelerates Select/Select0 by sampling the word containing every
+// ss-th 1-bit (and, separately, every ss-th 0-bit). Select/Select0 jump to the
+// nearest sample and scan forward only a short distance from there, instead of
+// binary-searching the whole rank index.
+type selectIndex struct {
+	// ss is the sampling rate: every ss-th 1-bit (and 0-bit) is sampled.
+	ss int
+	// ones holds, for each k, the word index containing the (k*ss)-th 1-bit.
+	ones []int
+	// zeros holds, for each k, the word index containing the (k*ss)-th 0-bit.
+	zeros []int
+	// nextOne and nextZero are the next 1-bit/0-bit cumulative counts to sample.
+	nextOne, nextZero int
+}
+
+// newSelectIndex creates a new selectIndex sampling every ss-th bit.
+func newSelectIndex(ss int) selectIndex {
+	if ss <= 0 {
+		ss = DefaultSs
+	}
+	return selectIndex{
+		ss:       ss,
+		nextOne:  ss,
+		nextZero: ss,
+	}
+}
+
+// update records samples for word bitsIndex given the cumulative 1-bit count
+// onesCount and 0-bit count zerosCount through that word, inclusive.
+func (s *selectIndex) update(bitsIndex, onesCount, zerosCount int) {
+	for onesCount >= s.nextOne {
+		s.ones = append(s.ones, bitsIndex)
+		s.nextOne += s.ss
+	}
+	for zerosCount >= s.nextZero {
+		s.zeros = append(s.zeros, bitsIndex)
+		s.nextZero += s.ss
+	}
+}
+
+// sampleOne returns the word index to start scanning forward from when
+// looking for the 1-bit with the given rank. ones[k-1] holds the word of the
+// (k*ss)-th 1-bit, which is always at or before any rank in bucket k.
+func (s *selectIndex) sampleOne(rank int) int {
+	k := (rank - 1) / s.ss
+	if k == 0 || k-1 >= len(s.ones) {
+		return 0
+	}
+	return s.ones[k-1]
+}
+
+// sampleZero returns the word index to start scanning forward from when
+// looking for the 0-bit with the given rank. zeros[k-1] holds the word of the
+// (k*ss)-th 0-bit, which is always at or before any rank in bucket k.
+func (s *selectIndex) sampleZero(rank int) int {
+	k := (rank - 1) / s.ss
+	if k == 0 || k-1 >= len(s.zeros) {
+		return 0
+	}
+	return s.zeros[k-1]
+}
+
+// selectInWord returns the position within x of the rank-th (1-indexed) set bit.
+// It assumes such a bit exists; callers are expected to have verified the word
+// contains at least rank set bits.
+func selectInWord(x uint64, rank int) int {
+	for i := 0; i < bitsSize; i++ {
+		if x&(1<<i) != 0 {
+			rank--
+			if rank == 0 {
+				return i
+			}
+		}
+	}
+	return bitsSize - 1
+}
@@ -0,0 +1,270 @@
+package dictionary
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// serializeMagic identifies the on-disk format produced by WriteTo.
+	serializeMagic = "SUCD"
+
+	// serializeVersion is the current on-disk format version. ReadFrom rejects
+	// any other version so the format can evolve without silently misreading
+	// data written by an incompatible release. Version 2 packs the bit array
+	// as 64-bit words instead of bytes, following Dictionary's word-aligned
+	// storage.
+	serializeVersion = 2
+
+	// wideFlag is set in the serialized flags byte when the large rank index
+	// was stored as largeRank64 rather than largeRank32.
+	wideFlag = 1 << 0
+
+	// maxReadChunk bounds how much byteReader.read and readVarintDeltas
+	// allocate per step, so a corrupt or adversarial length prefix can't force
+	// an allocation sized off the untrusted count before any of the data it
+	// claims to describe has actually been read off the wire.
+	maxReadChunk = 1 << 20
+)
+
+// WriteTo writes a versioned binary encoding of d to w and returns the number of
+// bytes written. The format stores the raw bit array, the small rank index as
+// packed uint16 values, and the large rank index and select-sampling index as
+// varint-encoded deltas, so ReadFrom can reconstruct the Dictionary without
+// repeating CreateIndex's work. The encoding round-trips regardless of whether
+// the large rank index was stored as 32-bit or 64-bit entries.
+func (d *Dictionary) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	cw.write([]byte(serializeMagic))
+	cw.writeByte(serializeVersion)
+
+	flags := byte(0)
+	if _, wide := d.rank.large.(largeRank64); wide {
+		flags |= wideFlag
+	}
+	cw.writeByte(flags)
+
+	cw.writeUvarint(uint64(d.sel.ss))
+	cw.writeUvarint(uint64(len(d.bits)))
+	bitWords := make([]byte, len(d.bits)*8)
+	for i, w := range d.bits {
+		binary.BigEndian.PutUint64(bitWords[i*8:], w)
+	}
+	cw.write(bitWords)
+
+	cw.writeUvarint(uint64(len(d.rank.small)))
+	small := make([]byte, len(d.rank.small)*2)
+	for i, s := range d.rank.small {
+		binary.BigEndian.PutUint16(small[i*2:], s)
+	}
+	cw.write(small)
+
+	cw.writeVarintDeltas(d.rank.large.len(), d.rank.large.get)
+	cw.writeVarintDeltas(len(d.sel.ones), func(i int) int { return d.sel.ones[i] })
+	cw.writeVarintDeltas(len(d.sel.zeros), func(i int) int { return d.sel.zeros[i] })
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// ReadFrom reads a Dictionary previously written by WriteTo.
+func ReadFrom(r io.Reader) (*Dictionary, error) {
+	br := &byteReader{r: bufio.NewReader(r)}
+
+	magic := br.read(len(serializeMagic))
+	version := br.readByte()
+	flags := br.readByte()
+	if br.err != nil {
+		return nil, br.err
+	}
+	if string(magic) != serializeMagic {
+		return nil, errors.New("dictionary: invalid magic header")
+	}
+	if version != serializeVersion {
+		return nil, fmt.Errorf("dictionary: unsupported version %d", version)
+	}
+
+	d := new(Dictionary)
+	d.sel.ss = int(br.readUvarint())
+
+	nWords := br.readCount(8)
+	bitWords := br.read(nWords * 8)
+	if br.err != nil {
+		return nil, br.err
+	}
+	d.bits = make([]uint64, nWords)
+	for i := range d.bits {
+		d.bits[i] = binary.BigEndian.Uint64(bitWords[i*8:])
+	}
+
+	nSmall := br.readCount(2)
+	smallBytes := br.read(nSmall * 2)
+	if br.err != nil {
+		return nil, br.err
+	}
+	small := make([]uint16, nSmall)
+	for i := range small {
+		small[i] = binary.BigEndian.Uint16(smallBytes[i*2:])
+	}
+	d.rank.small = small
+
+	large := br.readVarintDeltas()
+	if flags&wideFlag != 0 {
+		l := make(largeRank64, len(large))
+		for i, v := range large {
+			l.set(i, v)
+		}
+		d.rank.large = l
+	} else {
+		l := make(largeRank32, len(large))
+		for i, v := range large {
+			l.set(i, v)
+		}
+		d.rank.large = l
+	}
+
+	d.sel.ones = br.readVarintDeltas()
+	d.sel.zeros = br.readVarintDeltas()
+
+	if br.err != nil {
+		return nil, br.err
+	}
+	return d, nil
+}
+
+// countingWriter wraps a bufio.Writer, tallying bytes written and latching the
+// first error so callers can chain writes without checking each one.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) write(p []byte) {
+	if cw.err != nil {
+		return
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+func (cw *countingWriter) writeByte(b byte) {
+	cw.write([]byte{b})
+}
+
+func (cw *countingWriter) writeUvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	cw.write(buf[:n])
+}
+
+// writeVarintDeltas writes n followed by n values (obtained via at) encoded as
+// varint deltas from the previous value, which is compact for the monotonically
+// increasing cumulative counts used throughout this package.
+func (cw *countingWriter) writeVarintDeltas(n int, at func(i int) int) {
+	cw.writeUvarint(uint64(n))
+	prev := 0
+	for i := 0; i < n; i++ {
+		v := at(i)
+		cw.writeUvarint(uint64(v - prev))
+		prev = v
+	}
+}
+
+// byteReader wraps a bufio.Reader, latching the first error so callers can
+// chain reads without checking each one.
+type byteReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+// read returns the next n bytes from the stream. It grows its buffer in
+// maxReadChunk steps rather than allocating n bytes up front, so a bogus
+// length prefix fails with a read error instead of an out-of-memory crash.
+func (br *byteReader) read(n int) []byte {
+	if br.err != nil {
+		return nil
+	}
+	if n < 0 {
+		br.err = errors.New("dictionary: negative length")
+		return nil
+	}
+	buf := make([]byte, 0, min(n, maxReadChunk))
+	for len(buf) < n {
+		start := len(buf)
+		buf = append(buf, make([]byte, min(n-start, maxReadChunk))...)
+		if _, err := io.ReadFull(br.r, buf[start:]); err != nil {
+			br.err = err
+			return nil
+		}
+	}
+	return buf
+}
+
+func (br *byteReader) readByte() byte {
+	if br.err != nil {
+		return 0
+	}
+	b, err := br.r.ReadByte()
+	br.err = err
+	return b
+}
+
+func (br *byteReader) readUvarint() uint64 {
+	if br.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(br.r)
+	br.err = err
+	return v
+}
+
+// readCount reads a varint-encoded element count meant to be multiplied by
+// elemSize into a byte length, and rejects it up front if that multiplication
+// would overflow int, rather than deriving a wrapped-around byte length that
+// a bounded read could no longer catch.
+func (br *byteReader) readCount(elemSize int) int {
+	v := br.readUvarint()
+	if br.err != nil {
+		return 0
+	}
+	if v > uint64(math.MaxInt/elemSize) {
+		br.err = errors.New("dictionary: declared length too large")
+		return 0
+	}
+	return int(v)
+}
+
+// readVarintDeltas reads back a slice written by writeVarintDeltas. Its
+// capacity is grown via append rather than allocated from n up front, since
+// each element consumes at least a byte of the stream, so a bogus n fails
+// with a read error instead of an out-of-memory crash.
+func (br *byteReader) readVarintDeltas() []int {
+	if br.err != nil {
+		return nil
+	}
+	n := int(br.readUvarint())
+	if n < 0 {
+		br.err = errors.New("dictionary: negative length")
+		return nil
+	}
+	out := make([]int, 0, min(n, maxReadChunk))
+	prev := 0
+	for i := 0; i < n; i++ {
+		v := br.readUvarint()
+		if br.err != nil {
+			return nil
+		}
+		prev += int(v)
+		out = append(out, prev)
+	}
+	return out
+}
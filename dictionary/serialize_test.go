@@ -0,0 +1,150 @@
+package dictionary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestWriteToReadFrom(t *testing.T) {
+	size := 1000000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"all one bits":  oneBits(t, size),
+		"random bits":   randBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			d := newDictionary(t, test)
+
+			var buf bytes.Buffer
+			n, err := d.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("WriteTo() err = %v", err)
+			}
+			if got := int64(buf.Len()); n != got {
+				t.Fatalf("WriteTo() = %d; want %d", n, got)
+			}
+
+			got, err := ReadFrom(&buf)
+			if err != nil {
+				t.Fatalf("ReadFrom() err = %v", err)
+			}
+			if got.Len() != d.Len() {
+				t.Fatalf("ReadFrom().Len() = %d; want %d", got.Len(), d.Len())
+			}
+			for i := range test {
+				if got.Bit(i) != d.Bit(i) {
+					t.Fatalf("ReadFrom().Bit(%d) = %t; want %t", i, got.Bit(i), d.Bit(i))
+				}
+				if got.Rank(i) != d.Rank(i) {
+					t.Fatalf("ReadFrom().Rank(%d) = %d; want %d", i, got.Rank(i), d.Rank(i))
+				}
+			}
+			rank := 0
+			for i, b := range test {
+				if b {
+					rank++
+					if got := got.Select(rank); got != i {
+						t.Fatalf("ReadFrom().Select(%d) = %d; want %d", rank, got, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReadFromInvalidHeader(t *testing.T) {
+	d := newDictionary(t, randBits(t, 1000))
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() err = %v", err)
+	}
+	encoded := buf.Bytes()
+
+	t.Run("bad magic", func(t *testing.T) {
+		corrupt := append([]byte(nil), encoded...)
+		corrupt[0] = 'X'
+		if _, err := ReadFrom(bytes.NewReader(corrupt)); err == nil {
+			t.Fatal("ReadFrom() err = nil; want error")
+		}
+	})
+
+	t.Run("bad version", func(t *testing.T) {
+		corrupt := append([]byte(nil), encoded...)
+		corrupt[len(serializeMagic)] = serializeVersion + 1
+		if _, err := ReadFrom(bytes.NewReader(corrupt)); err == nil {
+			t.Fatal("ReadFrom() err = nil; want error")
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		if _, err := ReadFrom(bytes.NewReader(encoded[:len(encoded)/2])); err == nil {
+			t.Fatal("ReadFrom() err = nil; want error")
+		}
+	})
+
+	t.Run("oversized word count", func(t *testing.T) {
+		// magic + version + flags + ss=0, then a word count far larger than
+		// the stream could possibly hold: ReadFrom must fail, not allocate
+		// off the untrusted count.
+		var buf bytes.Buffer
+		buf.WriteString(serializeMagic)
+		buf.WriteByte(serializeVersion)
+		buf.WriteByte(0)
+		var varint [binary.MaxVarintLen64]byte
+		buf.Write(varint[:binary.PutUvarint(varint[:], 0)])
+		buf.Write(varint[:binary.PutUvarint(varint[:], 1<<40)])
+		if _, err := ReadFrom(&buf); err == nil {
+			t.Fatal("ReadFrom() err = nil; want error")
+		}
+	})
+
+	t.Run("word count overflows byte length", func(t *testing.T) {
+		// A word count large enough that multiplying it by 8 (the bytes per
+		// uint64) wraps around int, which would otherwise let it sail past
+		// the bounded read and reach make([]uint64, nWords) with the
+		// original, un-wrapped huge count. ReadFrom must reject it outright
+		// rather than deriving a wrapped byte length from it.
+		var buf bytes.Buffer
+		buf.WriteString(serializeMagic)
+		buf.WriteByte(serializeVersion)
+		buf.WriteByte(0)
+		var varint [binary.MaxVarintLen64]byte
+		buf.Write(varint[:binary.PutUvarint(varint[:], 0)])
+		buf.Write(varint[:binary.PutUvarint(varint[:], 1<<61)])
+		if _, err := ReadFrom(&buf); err == nil {
+			t.Fatal("ReadFrom() err = nil; want error")
+		}
+	})
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	size := 10000000
+	d := newDictionary(b, randBits(b, size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		if _, err := d.WriteTo(&buf); err != nil {
+			b.Fatalf("WriteTo() err = %v", err)
+		}
+	}
+}
+
+func BenchmarkReadFrom(b *testing.B) {
+	size := 10000000
+	d := newDictionary(b, randBits(b, size))
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		b.Fatalf("WriteTo() err = %v", err)
+	}
+	encoded := buf.Bytes()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadFrom(bytes.NewReader(encoded)); err != nil {
+			b.Fatalf("ReadFrom() err = %v", err)
+		}
+	}
+}
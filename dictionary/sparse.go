@@ -0,0 +1,150 @@
+package dictionary
+
+import "math"
+
+// SparseDictionary is an Elias-Fano encoded bit vector: an alternative to
+// Dictionary for bitsets whose 1-bits are sparse (or, symmetrically, whose
+// 0-bits are), where space proportional to the universe size wastes most of
+// it. Space is O(n log(u/n)) bits for n ones in a universe of size u, instead
+// of Dictionary's O(u).
+//
+// Each 1-bit's position is split into a high part and a low part of l bits,
+// where l is chosen so the low parts are uniform-ish in width. The low parts
+// are packed contiguously; the high parts are unary-coded into a bit vector
+// where the k-th 1-bit contributes a 1 followed by zeros for the gap to the
+// next distinct high value, so Select can recover a high part in O(1) via
+// Select on that vector and Rank via the matching Select0.
+type SparseDictionary struct {
+	// u is the universe size: the number of bits in the conceptual bit vector.
+	u int
+	// n is the number of 1-bits stored.
+	n int
+	// l is the width, in bits, of the low part of each position.
+	l uint
+	// low holds the low l bits of each of the n stored positions, in order.
+	low *packedInts
+	// upper is the unary-coded high parts, supporting O(1) Select/Select0.
+	upper *Dictionary
+}
+
+// NewSparse builds a SparseDictionary from bits, storing the positions of its
+// 1-bits with Elias-Fano coding.
+func NewSparse(bits []bool) *SparseDictionary {
+	u := len(bits)
+	var positions []int
+	for i, b := range bits {
+		if b {
+			positions = append(positions, i)
+		}
+	}
+	n := len(positions)
+
+	s := &SparseDictionary{u: u, n: n, l: lowBitsWidth(u, n)}
+	s.low = newPackedInts(n, s.l)
+	if n == 0 {
+		return s
+	}
+
+	maxHigh := (u - 1) >> s.l
+	s.upper = New(n + maxHigh + 1)
+	for k, pos := range positions {
+		high := pos >> s.l
+		low := uint64(pos) & s.lowMask()
+		s.low.set(k, low)
+		s.upper.SetBit(high+k, true)
+	}
+	s.upper.CreateIndex()
+	return s
+}
+
+// lowBitsWidth returns ⌈log2(u/n)⌉, the low-part width used to split stored
+// positions so the upper (unary-coded) part stays close to n+u/2^l bits.
+func lowBitsWidth(u, n int) uint {
+	if n <= 0 || u <= n {
+		return 0
+	}
+	l := math.Ceil(math.Log2(float64(u) / float64(n)))
+	if l < 0 {
+		l = 0
+	}
+	return uint(l)
+}
+
+// lowMask returns the mask selecting the low l bits of a position.
+func (s *SparseDictionary) lowMask() uint64 {
+	return uint64(1)<<s.l - 1
+}
+
+// highAt returns the high part of the k-th stored position (0-indexed),
+// recovered from the unary-coded upper vector: the k-th 1-bit sits at
+// high+k, so subtracting k back out recovers high.
+func (s *SparseDictionary) highAt(k int) int {
+	return s.upper.Select(k+1) - k
+}
+
+// Len returns the total number of bits in the bit vector.
+func (s *SparseDictionary) Len() int {
+	return s.u
+}
+
+// Bit returns true if the bit at the given position is 1, and false otherwise.
+func (s *SparseDictionary) Bit(pos int) bool {
+	return s.rankLessThan(pos+1) > s.rankLessThan(pos)
+}
+
+// Rank returns the number of 1-bits up to and including the given position.
+func (s *SparseDictionary) Rank(pos int) int {
+	return s.rankLessThan(pos + 1)
+}
+
+// Rank0 returns the number of 0-bits up to and including the given position.
+func (s *SparseDictionary) Rank0(pos int) int {
+	return pos - s.Rank(pos) + 1
+}
+
+// rankLessThan returns the number of stored positions strictly less than x,
+// by locating x's high part in the upper vector's unary encoding (via
+// Select0) and then comparing low bits within that bucket.
+func (s *SparseDictionary) rankLessThan(x int) int {
+	if s.n == 0 || x <= 0 {
+		return 0
+	}
+	if x >= s.u {
+		return s.n
+	}
+
+	high := x >> s.l
+	start := 0
+	if high > 0 {
+		start = s.upper.Select0(high) + 1 - high
+	}
+
+	low := uint64(x) & s.lowMask()
+	idx := start
+	for idx < s.n && s.highAt(idx) == high && s.low.get(idx) < low {
+		idx++
+	}
+	return idx
+}
+
+// Select returns the smallest position of the 1-bit with the specified rank.
+// It is O(1): the high part comes directly from Select on the upper vector,
+// and the low part is a direct packedInts lookup.
+func (s *SparseDictionary) Select(rank int) int {
+	if rank <= 0 {
+		return 0
+	}
+	if rank > s.n {
+		return s.u
+	}
+	high := s.highAt(rank - 1)
+	low := s.low.get(rank - 1)
+	return high<<s.l | int(low)
+}
+
+// Select0 returns the smallest position of the 0-bit with the specified rank.
+// Elias-Fano stores only 1-bit positions, so unlike Select this falls back to
+// binary search over Rank0 rather than running in O(1).
+func (s *SparseDictionary) Select0(rank int) int {
+	return selectByRank(s.u, rank, s.Rank0)
+}
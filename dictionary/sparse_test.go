@@ -0,0 +1,217 @@
+package dictionary
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// sparseBits returns size bits with roughly one 1-bit per every 64 positions,
+// sparse enough for Elias-Fano to be worthwhile.
+func sparseBits(t testing.TB, size int) []bool {
+	t.Helper()
+	b := make([]bool, size)
+	for i := 0; i < size/64; i++ {
+		b[rand.Intn(size)] = true
+	}
+	return b
+}
+
+func TestSparseLen(t *testing.T) {
+	tests := map[string][]bool{
+		"empty":      {},
+		"all zero":   zeroBits(t, 1000),
+		"sparse":     sparseBits(t, 100000),
+		"single bit": append(zeroBits(t, 999), true),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := NewSparse(test)
+			if got, want := s.Len(), len(test); got != want {
+				t.Fatalf("Len() = %d; want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestSparseBit(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"sparse bits":   sparseBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := NewSparse(test)
+			for i, want := range test {
+				if got := s.Bit(i); got != want {
+					t.Fatalf("Bit(%d) = %t; want %t", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSparseRank(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"sparse bits":   sparseBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := NewSparse(test)
+			want := 0
+			for i, b := range test {
+				if b {
+					want++
+				}
+				if got := s.Rank(i); got != want {
+					t.Fatalf("Rank(%d) = %d; want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSparseRank0(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"sparse bits":   sparseBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := NewSparse(test)
+			want := 0
+			for i, b := range test {
+				if !b {
+					want++
+				}
+				if got := s.Rank0(i); got != want {
+					t.Fatalf("Rank0(%d) = %d; want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSparseSelect(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"sparse bits":   sparseBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := NewSparse(test)
+			rank := 0
+			td := make(map[int]int)
+			for i, b := range test {
+				if b {
+					rank++
+					td[rank] = i
+				}
+			}
+			td[len(test)+100] = s.Len()
+			for rank, want := range td {
+				if got := s.Select(rank); got != want {
+					t.Fatalf("Select(%d) = %d; want %d", rank, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSparseSelect0(t *testing.T) {
+	size := 100000
+	tests := map[string][]bool{
+		"all zero bits": zeroBits(t, size),
+		"sparse bits":   sparseBits(t, size),
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := NewSparse(test)
+			rank := 0
+			td := make(map[int]int)
+			for i, b := range test {
+				if !b {
+					rank++
+					td[rank] = i
+				}
+			}
+			td[len(test)+100] = s.Len()
+			for rank, want := range td {
+				if got := s.Select0(rank); got != want {
+					t.Fatalf("Select0(%d) = %d; want %d", rank, got, want)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSparseSelect(b *testing.B) {
+	size := 10000000
+	s := NewSparse(sparseBits(b, size))
+	r := rand.Intn(s.n) + 1
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Select(r)
+		}
+	})
+}
+
+func FuzzSparseRank(f *testing.F) {
+	for _, size := range []uint32{1, 10, 100, 1000, 10000, 100000} {
+		f.Add(size)
+	}
+	f.Fuzz(func(t *testing.T, size uint32) {
+		bs := sparseBits(t, int(size))
+		s := NewSparse(bs)
+		want := 0
+		for i, b := range bs {
+			if b {
+				want++
+			}
+			if got := s.Rank(i); got != want {
+				t.Fatalf("size = %d; Rank(%d) = %d; want %d", size, i, got, want)
+			}
+		}
+	})
+}
+
+func FuzzSparseSelect(f *testing.F) {
+	for _, size := range []uint32{1, 10, 100, 1000, 10000, 100000} {
+		f.Add(size)
+	}
+	f.Fuzz(func(t *testing.T, size uint32) {
+		bs := sparseBits(t, int(size))
+		s := NewSparse(bs)
+		rank := 0
+		td := make(map[int]int)
+		for i, b := range bs {
+			if b {
+				rank++
+				td[rank] = i
+			}
+		}
+		td[len(bs)+100] = s.Len()
+		for rank, want := range td {
+			if got := s.Select(rank); got != want {
+				t.Fatalf("size = %d; Select(%d) = %d; want %d", size, rank, got, want)
+			}
+		}
+	})
+}